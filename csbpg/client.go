@@ -0,0 +1,128 @@
+package csbpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/rds/auth"
+	_ "github.com/lib/pq"
+)
+
+// Config carries the provider-level connection settings used to build a
+// Client.
+type Config struct {
+	Host          string
+	Port          int
+	Username      string
+	Password      string
+	Database      string
+	DataOwnerRole string
+
+	SSLMode       string
+	SSLRootCert   string
+	SSLClientCert string
+	SSLClientKey  string
+	AWSRDSIAMAuth bool
+
+	ConnectTimeout     int
+	ApplicationName    string
+	MaxOpenConnections int
+	MaxIdleConnections int
+	ConnMaxLifetime    time.Duration
+}
+
+// Client wraps a connection to the configured PostgreSQL database along with
+// the provider-level settings resources need in order to operate.
+type Client struct {
+	db            *sql.DB
+	dataOwnerRole string
+}
+
+// NewClient opens a connection pool to the database described by config. The
+// pool is shared across every resource configured against this provider, so
+// SetMaxOpenConns/SetMaxIdleConns/SetConnMaxLifetime only need to run once.
+func NewClient(config Config) (*Client, error) {
+	dsn, err := config.connectionString()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connection to postgres: %w", err)
+	}
+
+	db.SetMaxOpenConns(config.MaxOpenConnections)
+	db.SetMaxIdleConns(config.MaxIdleConnections)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	return &Client{
+		db:            db,
+		dataOwnerRole: config.DataOwnerRole,
+	}, nil
+}
+
+// connectionString assembles a libpq DSN from the configured options. SSL
+// material and the password are only included when the chosen sslmode and
+// auth method actually need them.
+func (c Config) connectionString() (string, error) {
+	password := c.Password
+	if c.AWSRDSIAMAuth {
+		token, err := c.rdsAuthToken()
+		if err != nil {
+			return "", err
+		}
+		password = token
+	}
+
+	params := []string{
+		"host=" + c.Host,
+		fmt.Sprintf("port=%d", c.Port),
+		"user=" + c.Username,
+		"password=" + password,
+		"database=" + c.Database,
+		"sslmode=" + c.SSLMode,
+		fmt.Sprintf("connect_timeout=%d", c.ConnectTimeout),
+		fmt.Sprintf("application_name=%s", c.ApplicationName),
+	}
+
+	if c.SSLMode == "verify-ca" || c.SSLMode == "verify-full" {
+		params = append(params, "sslinline=true", fmt.Sprintf("sslrootcert='%s'", c.SSLRootCert))
+		if c.SSLClientCert != "" {
+			params = append(params,
+				fmt.Sprintf("sslcert='%s'", c.SSLClientCert),
+				fmt.Sprintf("sslkey='%s'", c.SSLClientKey),
+			)
+		}
+	}
+
+	return strings.Join(params, " "), nil
+}
+
+// rdsAuthToken obtains a short-lived IAM authentication token to use in
+// place of a static password, per the AWS RDS IAM database authentication
+// scheme.
+func (c Config) rdsAuthToken() (string, error) {
+	ctx := context.Background()
+
+	awsConfig, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS config for RDS IAM auth: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s:%d", c.Host, c.Port)
+	token, err := auth.BuildAuthToken(ctx, endpoint, awsConfig.Region, c.Username, awsConfig.Credentials)
+	if err != nil {
+		return "", fmt.Errorf("failed to build RDS IAM auth token: %w", err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,32 @@
+package csbpg_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/terraform-provider-csbpg/csbpg"
+)
+
+var _ = Describe("NewClient", func() {
+	It("rejects connection parameters it cannot dial", func() {
+		_, err := csbpg.NewClient(csbpg.Config{
+			Host:               "127.0.0.1",
+			Port:               1,
+			Username:           "postgres",
+			Password:           "postgres",
+			Database:           "postgres",
+			SSLMode:            "disable",
+			ConnectTimeout:     1,
+			ApplicationName:    "terraform-provider-csbpg",
+			MaxOpenConnections: 4,
+			MaxIdleConnections: 2,
+			ConnMaxLifetime:    time.Minute,
+		})
+
+		Expect(err).To(HaveOccurred())
+		Expect(strings.Contains(err.Error(), "failed to connect to postgres")).To(BeTrue())
+	})
+})
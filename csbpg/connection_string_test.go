@@ -0,0 +1,40 @@
+package csbpg
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config.connectionString", func() {
+	It("omits SSL material when sslmode is disable", func() {
+		dsn, err := Config{
+			Host:     "localhost",
+			Port:     5432,
+			Username: "postgres",
+			Password: "postgres",
+			Database: "postgres",
+			SSLMode:  "disable",
+		}.connectionString()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dsn).NotTo(ContainSubstring("sslrootcert"))
+	})
+
+	It("includes client cert material when sslmode is verify-full", func() {
+		dsn, err := Config{
+			Host:          "localhost",
+			Port:          5432,
+			Username:      "postgres",
+			Password:      "postgres",
+			Database:      "postgres",
+			SSLMode:       "verify-full",
+			SSLRootCert:   "ca",
+			SSLClientCert: "cert",
+			SSLClientKey:  "key",
+		}.connectionString()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dsn).To(ContainSubstring("sslcert='cert'"))
+		Expect(dsn).To(ContainSubstring("sslkey='key'"))
+	})
+})
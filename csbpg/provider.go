@@ -0,0 +1,162 @@
+// Package csbpg implements a Terraform provider for provisioning
+// PostgreSQL-backed service bindings the way the Cloud Service Broker does:
+// a shared data owner role per database and per-binding users that are
+// granted membership in it.
+package csbpg
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Provider returns the csbpg Terraform provider.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"host": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The address of the PostgreSQL server.",
+			},
+			"port": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The port of the PostgreSQL server.",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The username used to authenticate with the PostgreSQL server.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The password used to authenticate with the PostgreSQL server. Ignored when aws_rds_iam_auth is true.",
+			},
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The database to connect to and manage bindings against.",
+			},
+			"data_owner_role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The role that owns the data in the database. Binding users are granted membership in this role.",
+			},
+			"sslmode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "verify-ca",
+				ValidateFunc: validation.StringInSlice([]string{"disable", "require", "verify-ca", "verify-full"}, false),
+				Description:  "The SSL mode to use when connecting to the PostgreSQL server.",
+			},
+			"sslrootcert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM-encoded certificate authority used to verify the server certificate. Required when sslmode is verify-ca or verify-full.",
+			},
+			"aws_rds_iam_auth": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, password is ignored and an IAM authentication token is requested from AWS RDS at connect time.",
+			},
+			"connect_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     15,
+				Description: "Maximum time, in seconds, to wait for a new connection to the PostgreSQL server.",
+			},
+			"application_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "terraform-provider-csbpg",
+				Description: "The application_name reported to the PostgreSQL server for this provider's connections.",
+			},
+			"max_open_connections": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     4,
+				Description: "The maximum number of open connections the provider will hold to the PostgreSQL server.",
+			},
+			"max_idle_connections": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     2,
+				Description: "The maximum number of idle connections the provider will keep open to the PostgreSQL server.",
+			},
+			"conn_max_lifetime": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "The maximum lifetime, in seconds, of a connection to the PostgreSQL server. 0 means connections are not closed due to age.",
+			},
+			"clientcert": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Client certificate used to authenticate with the PostgreSQL server. Only meaningful when sslmode is verify-ca or verify-full.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cert": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "PEM-encoded client certificate.",
+						},
+						"key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "PEM-encoded client private key.",
+						},
+					},
+				},
+			},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"csbpg_binding_user":       resourceBindingUser(),
+			"csbpg_database":           resourceDatabase(),
+			"csbpg_role":               resourceRole(),
+			"csbpg_grant":              resourceGrant(),
+			"csbpg_default_privileges": resourceDefaultPrivileges(),
+		},
+		ConfigureContextFunc: configure,
+	}
+}
+
+func configure(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	config := Config{
+		Host:               d.Get("host").(string),
+		Port:               d.Get("port").(int),
+		Username:           d.Get("username").(string),
+		Password:           d.Get("password").(string),
+		Database:           d.Get("database").(string),
+		DataOwnerRole:      d.Get("data_owner_role").(string),
+		SSLMode:            d.Get("sslmode").(string),
+		SSLRootCert:        d.Get("sslrootcert").(string),
+		AWSRDSIAMAuth:      d.Get("aws_rds_iam_auth").(bool),
+		ConnectTimeout:     d.Get("connect_timeout").(int),
+		ApplicationName:    d.Get("application_name").(string),
+		MaxOpenConnections: d.Get("max_open_connections").(int),
+		MaxIdleConnections: d.Get("max_idle_connections").(int),
+		ConnMaxLifetime:    time.Duration(d.Get("conn_max_lifetime").(int)) * time.Second,
+	}
+
+	if certs, ok := d.Get("clientcert").([]interface{}); ok && len(certs) == 1 {
+		cert := certs[0].(map[string]interface{})
+		config.SSLClientCert = cert["cert"].(string)
+		config.SSLClientKey = cert["key"].(string)
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,284 @@
+package csbpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+// resourceBindingUser manages a PostgreSQL login that is granted membership
+// in the provider's data_owner_role. Objects the user creates are owned by
+// the user itself until it is destroyed, when their ownership is reassigned
+// to data_owner_role.
+func resourceBindingUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceBindingUserCreate,
+		ReadContext:   resourceBindingUserRead,
+		UpdateContext: resourceBindingUserUpdate,
+		DeleteContext: resourceBindingUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceBindingUserImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the binding user to create.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "The password of the binding user.",
+			},
+			"legacy_binding_group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of the legacy binding_group role this user may still be a member of. When set, Create, and Update when this attribute changes, migrate the user away from it onto data_owner_role. Read and import do not run the migration.",
+			},
+		},
+	}
+}
+
+func resourceBindingUserCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	username := d.Get("username").(string)
+	password := d.Get("password").(string)
+
+	if err := withTx(client.db, func(tx *sql.Tx) error {
+		if err := ensureDataOwnerRole(tx, client.dataOwnerRole); err != nil {
+			return err
+		}
+
+		exists, err := roleExistsTx(tx, username)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			// Adopt a role that was already provisioned out-of-band (e.g. by
+			// the legacy broker) instead of failing with "role already
+			// exists".
+			if _, err := tx.Exec(fmt.Sprintf(
+				"ALTER USER %s WITH PASSWORD %s",
+				pq.QuoteIdentifier(username), pq.QuoteLiteral(password),
+			)); err != nil {
+				return fmt.Errorf("failed to update password for existing binding user %q: %w", username, err)
+			}
+
+			if _, err := tx.Exec(fmt.Sprintf(
+				"GRANT %s TO %s", pq.QuoteIdentifier(client.dataOwnerRole), pq.QuoteIdentifier(username),
+			)); err != nil {
+				return fmt.Errorf("failed to grant data owner role %q to existing binding user %q: %w", client.dataOwnerRole, username, err)
+			}
+		} else {
+			if _, err := tx.Exec(fmt.Sprintf(
+				"CREATE USER %s WITH PASSWORD %s IN ROLE %s",
+				pq.QuoteIdentifier(username), pq.QuoteLiteral(password), pq.QuoteIdentifier(client.dataOwnerRole),
+			)); err != nil {
+				return fmt.Errorf("failed to create binding user %q: %w", username, err)
+			}
+		}
+
+		if legacyGroup, ok := d.GetOk("legacy_binding_group"); ok {
+			if err := migrateLegacyBindingUser(tx, username, legacyGroup.(string), client.dataOwnerRole); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(username)
+	return resourceBindingUserRead(context.Background(), d, meta)
+}
+
+func resourceBindingUserRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	username := d.Id()
+
+	exists, err := roleExists(client.db, username)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !exists {
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("username", username); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceBindingUserUpdate only ever reacts to a change in
+// legacy_binding_group, since username and password are both ForceNew. The
+// migration itself runs here (and in Create) rather than in Read,
+// since Read must stay side-effect free: it is called on every refresh.
+func resourceBindingUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	username := d.Id()
+
+	if legacyGroup, ok := d.GetOk("legacy_binding_group"); ok && d.HasChange("legacy_binding_group") {
+		if err := withTx(client.db, func(tx *sql.Tx) error {
+			return migrateLegacyBindingUser(tx, username, legacyGroup.(string), client.dataOwnerRole)
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceBindingUserRead(ctx, d, meta)
+}
+
+// resourceBindingUserImport accepts username as the import ID, so that a
+// pre-existing legacy binding user can be adopted into Terraform state
+// before being migrated off binding_group via legacy_binding_group.
+func resourceBindingUserImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	username := d.Id()
+	if err := d.Set("username", username); err != nil {
+		return nil, fmt.Errorf("failed to set username while importing binding user %q: %w", username, err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// migrateLegacyBindingUser re-attaches a user that was originally created
+// under the legacy "binding_group" scheme: it revokes membership in
+// legacyGroup, grants membership in dataOwnerRole, transfers ownership of
+// every object the user owns to dataOwnerRole, and rewrites the user's
+// ALTER DEFAULT PRIVILEGES entries to target dataOwnerRole instead of
+// legacyGroup. It is a no-op if the user is not a member of legacyGroup.
+func migrateLegacyBindingUser(tx *sql.Tx, username, legacyGroup, dataOwnerRole string) error {
+	var isLegacyMember bool
+	if err := tx.QueryRow("SELECT pg_has_role($1, $2, 'member')", username, legacyGroup).Scan(&isLegacyMember); err != nil {
+		return fmt.Errorf("failed to check legacy group membership for %q: %w", username, err)
+	}
+	if !isLegacyMember {
+		return nil
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("REVOKE %s FROM %s", pq.QuoteIdentifier(legacyGroup), pq.QuoteIdentifier(username))); err != nil {
+		return fmt.Errorf("failed to revoke legacy group %q from %q: %w", legacyGroup, username, err)
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("GRANT %s TO %s", pq.QuoteIdentifier(dataOwnerRole), pq.QuoteIdentifier(username))); err != nil {
+		return fmt.Errorf("failed to grant data owner role %q to %q: %w", dataOwnerRole, username, err)
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("REASSIGN OWNED BY %s TO %s", pq.QuoteIdentifier(username), pq.QuoteIdentifier(dataOwnerRole))); err != nil {
+		return fmt.Errorf("failed to reassign objects owned by %q to %q: %w", username, dataOwnerRole, err)
+	}
+
+	for _, objectType := range []string{"TABLES", "SEQUENCES", "FUNCTIONS"} {
+		if _, err := tx.Exec(fmt.Sprintf(
+			"ALTER DEFAULT PRIVILEGES FOR ROLE %s REVOKE ALL ON %s FROM %s",
+			pq.QuoteIdentifier(username), objectType, pq.QuoteIdentifier(legacyGroup),
+		)); err != nil {
+			return fmt.Errorf("failed to revoke legacy default privileges on %s for %q: %w", objectType, username, err)
+		}
+
+		if _, err := tx.Exec(fmt.Sprintf(
+			"ALTER DEFAULT PRIVILEGES FOR ROLE %s GRANT ALL ON %s TO %s",
+			pq.QuoteIdentifier(username), objectType, pq.QuoteIdentifier(dataOwnerRole),
+		)); err != nil {
+			return fmt.Errorf("failed to grant default privileges on %s to %q for %q: %w", objectType, dataOwnerRole, username, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceBindingUserDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	username := d.Id()
+
+	if err := withTx(client.db, func(tx *sql.Tx) error {
+		// Hand the user's objects to data_owner_role so they outlive the user,
+		// and drop its remaining privileges so that DROP USER can succeed.
+		if _, err := tx.Exec(fmt.Sprintf("REASSIGN OWNED BY %s TO %s", pq.QuoteIdentifier(username), pq.QuoteIdentifier(client.dataOwnerRole))); err != nil {
+			return fmt.Errorf("failed to reassign objects owned by %q to %q: %w", username, client.dataOwnerRole, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("DROP OWNED BY %s", pq.QuoteIdentifier(username))); err != nil {
+			return fmt.Errorf("failed to drop privileges owned by %q: %w", username, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("DROP USER %s", pq.QuoteIdentifier(username))); err != nil {
+			return fmt.Errorf("failed to drop binding user %q: %w", username, err)
+		}
+		return nil
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// ensureDataOwnerRole creates the shared data owner role if it does not
+// already exist. The role itself is never managed by a resource, since it
+// must outlive any single binding user.
+func ensureDataOwnerRole(tx *sql.Tx, role string) error {
+	exists, err := roleExistsTx(tx, role)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("CREATE ROLE %s", pq.QuoteIdentifier(role))); err != nil {
+		return fmt.Errorf("failed to create data owner role %q: %w", role, err)
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(
+		"ALTER DEFAULT PRIVILEGES FOR ROLE %s GRANT ALL ON TABLES TO %s",
+		pq.QuoteIdentifier(role), pq.QuoteIdentifier(role),
+	)); err != nil {
+		return fmt.Errorf("failed to set default table privileges for %q: %w", role, err)
+	}
+
+	return nil
+}
+
+func roleExists(db *sql.DB, role string) (bool, error) {
+	var exists bool
+	err := db.QueryRow("SELECT EXISTS(SELECT FROM pg_catalog.pg_roles WHERE rolname = $1)", role).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if role %q exists: %w", role, err)
+	}
+	return exists, nil
+}
+
+func roleExistsTx(tx *sql.Tx, role string) (bool, error) {
+	var exists bool
+	err := tx.QueryRow("SELECT EXISTS(SELECT FROM pg_catalog.pg_roles WHERE rolname = $1)", role).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if role %q exists: %w", role, err)
+	}
+	return exists, nil
+}
+
+// withTx runs fn inside a transaction, committing on success and rolling
+// back otherwise.
+func withTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,262 @@
+package csbpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+// resourceDatabase manages a PostgreSQL database, modeled after the
+// upstream Hashicorp postgresql provider's postgresql_database resource.
+func resourceDatabase() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseCreate,
+		ReadContext:   resourceDatabaseRead,
+		UpdateContext: resourceDatabaseUpdate,
+		DeleteContext: resourceDatabaseDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the database to create.",
+			},
+			"owner": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The role that owns the database.",
+			},
+			"encoding": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Computed:    true,
+				Description: "The character set encoding of the database.",
+			},
+			"lc_collate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Computed:    true,
+				Description: "The collation order (LC_COLLATE) of the database.",
+			},
+			"lc_ctype": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Computed:    true,
+				Description: "The character classification (LC_CTYPE) of the database.",
+			},
+			"template": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Computed:    true,
+				Description: "The template database from which to create the new database.",
+			},
+			"tablespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The tablespace in which to create the database.",
+			},
+			"connection_limit": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     -1,
+				Description: "The maximum number of concurrent connections allowed to the database. -1 means no limit.",
+			},
+			"allow_connections": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether other roles are allowed to connect to the database.",
+			},
+		},
+	}
+}
+
+func resourceDatabaseCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	name := d.Get("name").(string)
+
+	var opts []string
+	if owner, ok := d.GetOk("owner"); ok {
+		opts = append(opts, fmt.Sprintf("OWNER = %s", pq.QuoteIdentifier(owner.(string))))
+	}
+	if encoding, ok := d.GetOk("encoding"); ok {
+		opts = append(opts, fmt.Sprintf("ENCODING = %s", pq.QuoteLiteral(encoding.(string))))
+	}
+	if collate, ok := d.GetOk("lc_collate"); ok {
+		opts = append(opts, fmt.Sprintf("LC_COLLATE = %s", pq.QuoteLiteral(collate.(string))))
+	}
+	if ctype, ok := d.GetOk("lc_ctype"); ok {
+		opts = append(opts, fmt.Sprintf("LC_CTYPE = %s", pq.QuoteLiteral(ctype.(string))))
+	}
+	if template, ok := d.GetOk("template"); ok {
+		opts = append(opts, fmt.Sprintf("TEMPLATE = %s", pq.QuoteIdentifier(template.(string))))
+	}
+	if tablespace, ok := d.GetOk("tablespace"); ok {
+		opts = append(opts, fmt.Sprintf("TABLESPACE = %s", pq.QuoteIdentifier(tablespace.(string))))
+	}
+	opts = append(opts, fmt.Sprintf("CONNECTION LIMIT = %d", d.Get("connection_limit").(int)))
+	opts = append(opts, fmt.Sprintf("ALLOW_CONNECTIONS = %t", d.Get("allow_connections").(bool)))
+
+	stmt := fmt.Sprintf("CREATE DATABASE %s", pq.QuoteIdentifier(name))
+	if len(opts) > 0 {
+		stmt += " WITH " + joinWithSpace(opts)
+	}
+
+	if _, err := client.db.Exec(stmt); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to create database %q: %w", name, err))
+	}
+
+	d.SetId(name)
+	return resourceDatabaseRead(context.Background(), d, meta)
+}
+
+func resourceDatabaseRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	name := d.Id()
+
+	row := client.db.QueryRow(`
+		SELECT pg_catalog.pg_get_userbyid(datdba), pg_encoding_to_char(encoding), datcollate, datctype,
+		       COALESCE(spcname, 'pg_default'), datconnlimit, datallowconn
+		FROM pg_catalog.pg_database
+		LEFT JOIN pg_catalog.pg_tablespace ON pg_tablespace.oid = pg_database.dattablespace
+		WHERE datname = $1`, name)
+
+	var owner, encoding, collate, ctype, tablespace string
+	var connLimit int
+	var allowConnections bool
+	if err := row.Scan(&owner, &encoding, &collate, &ctype, &tablespace, &connLimit, &allowConnections); err != nil {
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("failed to read database %q: %w", name, err))
+	}
+
+	if err := setAll(d, map[string]interface{}{
+		"name":              name,
+		"owner":             owner,
+		"encoding":          encoding,
+		"lc_collate":        collate,
+		"lc_ctype":          ctype,
+		"tablespace":        tablespace,
+		"connection_limit":  connLimit,
+		"allow_connections": allowConnections,
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceDatabaseUpdate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	name := d.Id()
+
+	if d.HasChange("owner") {
+		owner := d.Get("owner").(string)
+		if _, err := client.db.Exec(fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", pq.QuoteIdentifier(name), pq.QuoteIdentifier(owner))); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to change owner of database %q: %w", name, err))
+		}
+	}
+
+	if d.HasChange("connection_limit") {
+		limit := d.Get("connection_limit").(int)
+		if _, err := client.db.Exec(fmt.Sprintf("ALTER DATABASE %s CONNECTION LIMIT %d", pq.QuoteIdentifier(name), limit)); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to change connection limit of database %q: %w", name, err))
+		}
+	}
+
+	if d.HasChange("tablespace") {
+		tablespace := d.Get("tablespace").(string)
+		if _, err := client.db.Exec(fmt.Sprintf("ALTER DATABASE %s SET TABLESPACE %s", pq.QuoteIdentifier(name), pq.QuoteIdentifier(tablespace))); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to change tablespace of database %q: %w", name, err))
+		}
+	}
+
+	if d.HasChange("allow_connections") {
+		allow := d.Get("allow_connections").(bool)
+		if _, err := client.db.Exec(fmt.Sprintf("ALTER DATABASE %s WITH ALLOW_CONNECTIONS %t", pq.QuoteIdentifier(name), allow)); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to change allow_connections of database %q: %w", name, err))
+		}
+	}
+
+	return resourceDatabaseRead(context.Background(), d, meta)
+}
+
+func resourceDatabaseDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	name := d.Id()
+
+	// Reassign ownership so that DROP DATABASE below is guaranteed to have
+	// the privileges to run, regardless of who ended up owning the database
+	// via out-of-band changes. This can safely run in its own transaction,
+	// since it does not race against new connections the way terminating
+	// backends does.
+	if err := withTx(client.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(fmt.Sprintf("ALTER DATABASE %s OWNER TO CURRENT_USER", pq.QuoteIdentifier(name))); err != nil {
+			return fmt.Errorf("failed to reassign owner of database %q before drop: %w", name, err)
+		}
+		return nil
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Terminate existing connections immediately before DROP DATABASE, on
+	// the same plain connection, so that no committed transaction boundary
+	// leaves a window for a new client to reconnect in between. DROP
+	// DATABASE cannot run inside a transaction block, so neither statement
+	// can use withTx.
+	ctx := context.Background()
+	conn, err := client.db.Conn(ctx)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to acquire a connection to drop database %q: %w", name, err))
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx,
+		"SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = $1 AND pid <> pg_backend_pid()",
+		name,
+	); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to terminate connections to database %q: %w", name, err))
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("DROP DATABASE %s", pq.QuoteIdentifier(name))); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to drop database %q: %w", name, err))
+	}
+
+	return nil
+}
+
+func joinWithSpace(parts []string) string {
+	result := ""
+	for i, part := range parts {
+		if i > 0 {
+			result += " "
+		}
+		result += part
+	}
+	return result
+}
+
+func setAll(d *schema.ResourceData, values map[string]interface{}) error {
+	for key, value := range values {
+		if key == "name" {
+			continue
+		}
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("failed to set %q: %w", key, err)
+		}
+	}
+	return nil
+}
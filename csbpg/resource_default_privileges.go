@@ -0,0 +1,141 @@
+package csbpg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+// resourceDefaultPrivileges manages ALTER DEFAULT PRIVILEGES entries, which
+// apply to objects created in the future rather than objects that already
+// exist.
+func resourceDefaultPrivileges() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDefaultPrivilegesCreate,
+		ReadContext:   resourceDefaultPrivilegesRead,
+		DeleteContext: resourceDefaultPrivilegesDelete,
+
+		Schema: map[string]*schema.Schema{
+			"owner": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role that will create the objects (FOR ROLE).",
+			},
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role the default privileges are granted to.",
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The schema new objects will be created in.",
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"table", "sequence", "function"}, false),
+				Description:  "The type of object the default privileges apply to.",
+			},
+			"privileges": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The privileges to grant by default, e.g. SELECT, INSERT, UPDATE, DELETE, ALL.",
+			},
+		},
+	}
+}
+
+func resourceDefaultPrivilegesCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	stmt := defaultPrivilegesStatement("GRANT", d, true)
+	if _, err := client.db.Exec(stmt); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set default privileges for role %q: %w", d.Get("owner").(string), err))
+	}
+
+	d.SetId(defaultPrivilegesID(d))
+	return resourceDefaultPrivilegesRead(context.Background(), d, meta)
+}
+
+// defaclObjType maps the object_type attribute to the single-character
+// pg_default_acl.defaclobjtype code (see the Postgres catalog docs).
+var defaclObjType = map[string]string{
+	"table":    "r",
+	"sequence": "S",
+	"function": "f",
+}
+
+func resourceDefaultPrivilegesRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	objectType := d.Get("object_type").(string)
+
+	rows, err := client.db.Query(`
+		SELECT 1
+		FROM pg_catalog.pg_default_acl
+		JOIN pg_catalog.pg_roles ON pg_roles.oid = pg_default_acl.defaclrole
+		JOIN pg_catalog.pg_namespace ON pg_namespace.oid = pg_default_acl.defaclnamespace
+		WHERE pg_roles.rolname = $1 AND pg_namespace.nspname = $2 AND pg_default_acl.defaclobjtype = $3`,
+		d.Get("owner").(string), d.Get("schema").(string), defaclObjType[objectType])
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to read default privileges for role %q: %w", d.Get("owner").(string), err))
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceDefaultPrivilegesDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	stmt := defaultPrivilegesStatement("REVOKE", d, false)
+	if _, err := client.db.Exec(stmt); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to revoke default privileges for role %q: %w", d.Get("owner").(string), err))
+	}
+
+	return nil
+}
+
+func defaultPrivilegesStatement(verb string, d *schema.ResourceData, grant bool) string {
+	owner := d.Get("owner").(string)
+	role := d.Get("role").(string)
+	schemaName := d.Get("schema").(string)
+	objectType := strings.ToUpper(d.Get("object_type").(string))
+	privileges := strings.Join(toStringSlice(d.Get("privileges").(*schema.Set).List()), ", ")
+
+	if grant {
+		return fmt.Sprintf(
+			"ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA %s %s %s ON %sS TO %s",
+			pq.QuoteIdentifier(owner), pq.QuoteIdentifier(schemaName), verb, privileges, objectType, pq.QuoteIdentifier(role),
+		)
+	}
+
+	return fmt.Sprintf(
+		"ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA %s %s %s ON %sS FROM %s",
+		pq.QuoteIdentifier(owner), pq.QuoteIdentifier(schemaName), verb, privileges, objectType, pq.QuoteIdentifier(role),
+	)
+}
+
+func defaultPrivilegesID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get("owner").(string),
+		d.Get("schema").(string),
+		d.Get("object_type").(string),
+		d.Get("role").(string),
+	}, "_")
+}
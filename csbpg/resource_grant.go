@@ -0,0 +1,281 @@
+package csbpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+// objectTypeKeyword maps the object_type attribute to the SQL keyword used in
+// GRANT/REVOKE ... ON <keyword>.
+var objectTypeKeyword = map[string]string{
+	"database": "DATABASE",
+	"schema":   "SCHEMA",
+	"table":    "TABLE",
+	"sequence": "SEQUENCE",
+	"function": "FUNCTION",
+}
+
+// resourceGrant manages GRANT/REVOKE of privileges on a set of objects to a
+// role.
+func resourceGrant() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGrantCreate,
+		ReadContext:   resourceGrantRead,
+		DeleteContext: resourceGrantDelete,
+
+		Schema: map[string]*schema.Schema{
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role to grant privileges to.",
+			},
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The database the objects live in.",
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The schema the objects live in. Required unless object_type is database.",
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"database", "schema", "table", "sequence", "function"}, false),
+				Description:  "The type of object to grant privileges on.",
+			},
+			"objects": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the objects to grant privileges on. Leave empty to target every object of object_type in the schema.",
+			},
+			"privileges": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The privileges to grant, e.g. SELECT, INSERT, UPDATE, DELETE, ALL.",
+			},
+			"with_grant_option": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Whether the role may in turn grant these privileges to others.",
+			},
+		},
+	}
+}
+
+func resourceGrantCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	stmt, err := grantStatement(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := client.db.Exec(stmt); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to grant privileges to role %q: %w", d.Get("role").(string), err))
+	}
+
+	d.SetId(grantID(d))
+	return resourceGrantRead(context.Background(), d, meta)
+}
+
+func resourceGrantRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	role := d.Get("role").(string)
+	database := d.Get("database").(string)
+	schemaName := d.Get("schema").(string)
+
+	var found bool
+	var err error
+	switch d.Get("object_type").(string) {
+	case "table":
+		found, err = tableGrantsExist(client.db, role, database, schemaName, toStringSlice(d.Get("privileges").(*schema.Set).List()))
+	case "database":
+		found, err = rowsExist(client.db,
+			`SELECT 1 FROM pg_catalog.pg_database d, aclexplode(d.datacl) acl
+			 JOIN pg_catalog.pg_roles r ON r.oid = acl.grantee
+			 WHERE d.datname = $1 AND r.rolname = $2`,
+			database, role)
+	case "schema":
+		found, err = rowsExist(client.db,
+			`SELECT 1 FROM pg_catalog.pg_namespace n, aclexplode(n.nspacl) acl
+			 JOIN pg_catalog.pg_roles r ON r.oid = acl.grantee
+			 WHERE n.nspname = $1 AND r.rolname = $2`,
+			schemaName, role)
+	case "sequence":
+		found, err = rowsExist(client.db,
+			`SELECT 1 FROM information_schema.role_usage_grants
+			 WHERE grantee = $1 AND object_schema = $2 AND object_catalog = $3`,
+			role, schemaName, database)
+	case "function":
+		found, err = rowsExist(client.db,
+			`SELECT 1 FROM information_schema.role_routine_grants
+			 WHERE grantee = $1 AND routine_schema = $2 AND routine_catalog = $3`,
+			role, schemaName, database)
+	}
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to read grants for role %q: %w", role, err))
+	}
+
+	if !found {
+		d.SetId("")
+	}
+
+	return nil
+}
+
+// tableGrantsExist reports whether every privilege in privileges is still
+// granted to role on at least one table in schemaName, so that an
+// out-of-band REVOKE of a single privilege is surfaced as drift rather than
+// only a full revoke of every privilege on every table.
+func tableGrantsExist(db *sql.DB, role, database, schemaName string, privileges []string) (bool, error) {
+	rows, err := db.Query(
+		`SELECT DISTINCT privilege_type FROM information_schema.role_table_grants
+		 WHERE grantee = $1 AND table_catalog = $2 AND table_schema = $3`,
+		role, database, schemaName)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	granted := map[string]bool{}
+	for rows.Next() {
+		var privilege string
+		if err := rows.Scan(&privilege); err != nil {
+			return false, err
+		}
+		granted[privilege] = true
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	for _, privilege := range privileges {
+		upper := strings.ToUpper(privilege)
+		if upper == "ALL" || upper == "ALL PRIVILEGES" {
+			// ALL is expanded into individual privilege_type rows.
+			if len(granted) == 0 {
+				return false, nil
+			}
+			continue
+		}
+		if !granted[upper] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func rowsExist(db *sql.DB, query string, args ...interface{}) (bool, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	return rows.Next(), nil
+}
+
+func resourceGrantDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	stmt, err := revokeStatement(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := client.db.Exec(stmt); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to revoke privileges from role %q: %w", d.Get("role").(string), err))
+	}
+
+	return nil
+}
+
+func grantStatement(d *schema.ResourceData) (string, error) {
+	privileges := toStringSlice(d.Get("privileges").(*schema.Set).List())
+	target, err := grantTarget(d)
+	if err != nil {
+		return "", err
+	}
+
+	stmt := fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(privileges, ", "), target, pq.QuoteIdentifier(d.Get("role").(string)))
+	if d.Get("with_grant_option").(bool) {
+		stmt += " WITH GRANT OPTION"
+	}
+
+	return stmt, nil
+}
+
+func revokeStatement(d *schema.ResourceData) (string, error) {
+	privileges := toStringSlice(d.Get("privileges").(*schema.Set).List())
+	target, err := grantTarget(d)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(privileges, ", "), target, pq.QuoteIdentifier(d.Get("role").(string))), nil
+}
+
+func grantTarget(d *schema.ResourceData) (string, error) {
+	objectType := d.Get("object_type").(string)
+	keyword, ok := objectTypeKeyword[objectType]
+	if !ok {
+		return "", fmt.Errorf("unsupported object_type %q", objectType)
+	}
+
+	if objectType == "database" {
+		return fmt.Sprintf("DATABASE %s", pq.QuoteIdentifier(d.Get("database").(string))), nil
+	}
+
+	if objectType == "schema" {
+		return fmt.Sprintf("SCHEMA %s", pq.QuoteIdentifier(d.Get("schema").(string))), nil
+	}
+
+	objects := toStringSlice(d.Get("objects").(*schema.Set).List())
+	if len(objects) == 0 {
+		return fmt.Sprintf("ALL %sS IN SCHEMA %s", keyword, pq.QuoteIdentifier(d.Get("schema").(string))), nil
+	}
+
+	schemaName := d.Get("schema").(string)
+	qualified := make([]string, len(objects))
+	for i, obj := range objects {
+		qualified[i] = fmt.Sprintf("%s.%s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(obj))
+	}
+
+	return fmt.Sprintf("%s %s", keyword, strings.Join(qualified, ", ")), nil
+}
+
+func grantID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get("database").(string),
+		d.Get("schema").(string),
+		d.Get("object_type").(string),
+		d.Get("role").(string),
+	}, "_")
+}
+
+func toStringSlice(values []interface{}) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = v.(string)
+	}
+	return result
+}
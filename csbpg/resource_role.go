@@ -0,0 +1,414 @@
+package csbpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+// resourceRole manages an arbitrary PostgreSQL role, exposing the full
+// attribute surface of CREATE ROLE rather than the narrow, data-owner-bound
+// shape of csbpg_binding_user.
+func resourceRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		UpdateContext: resourceRoleUpdate,
+		DeleteContext: resourceRoleDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the role.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The password for the role.",
+			},
+			"encrypted_password": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If true, the password is set with ENCRYPTED PASSWORD; if false, with PASSWORD.",
+			},
+			"login": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the role is allowed to log in.",
+			},
+			"superuser": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the role is a superuser.",
+			},
+			"create_database": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the role is allowed to create databases.",
+			},
+			"create_role": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the role is allowed to create other roles.",
+			},
+			"inherit": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the role inherits the privileges of roles it is a member of.",
+			},
+			"replication": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the role is allowed to initiate streaming replication.",
+			},
+			"bypass_row_level_security": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the role bypasses every row-level security policy.",
+			},
+			"connection_limit": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     -1,
+				Description: "The maximum number of concurrent connections the role can make. -1 means no limit.",
+			},
+			"valid_until": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "infinity",
+				Description: "The date and time after which the role's password is no longer valid.",
+			},
+			"roles": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Roles that this role should be a member of.",
+			},
+			"search_path": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The schema search path to set for this role.",
+			},
+			"skip_drop_role": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, DROP ROLE is skipped on destroy, leaving the role in place.",
+			},
+			"skip_reassign_owned": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, REASSIGN OWNED and DROP OWNED are skipped on destroy.",
+			},
+		},
+	}
+}
+
+func resourceRoleCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	name := d.Get("name").(string)
+
+	if err := withTx(client.db, func(tx *sql.Tx) error {
+		stmt := fmt.Sprintf("CREATE ROLE %s WITH %s", pq.QuoteIdentifier(name), roleAttributeOptions(d))
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to create role %q: %w", name, err)
+		}
+
+		if err := applyRoleMemberships(tx, name, nil, d.Get("roles").(*schema.Set).List()); err != nil {
+			return err
+		}
+
+		if err := applyRoleSearchPath(tx, name, d.Get("search_path").([]interface{})); err != nil {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	return resourceRoleRead(context.Background(), d, meta)
+}
+
+func resourceRoleRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	name := d.Id()
+
+	row := client.db.QueryRow(`
+		SELECT rolcanlogin, rolsuper, rolcreatedb, rolcreaterole, rolinherit, rolreplication,
+		       rolbypassrls, rolconnlimit, rolvaliduntil::text
+		FROM pg_catalog.pg_roles
+		WHERE rolname = $1`, name)
+
+	var login, superuser, createDatabase, createRole, inherit, replication, bypassRLS bool
+	var connLimit int
+	var validUntil sql.NullString
+	if err := row.Scan(&login, &superuser, &createDatabase, &createRole, &inherit, &replication,
+		&bypassRLS, &connLimit, &validUntil); err != nil {
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("failed to read role %q: %w", name, err))
+	}
+
+	memberships, err := roleMemberships(client.db, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values := map[string]interface{}{
+		"name":                      name,
+		"login":                     login,
+		"superuser":                 superuser,
+		"create_database":           createDatabase,
+		"create_role":               createRole,
+		"inherit":                   inherit,
+		"replication":               replication,
+		"bypass_row_level_security": bypassRLS,
+		"connection_limit":          connLimit,
+		"roles":                     memberships,
+	}
+	if validUntil.Valid {
+		values["valid_until"] = validUntil.String
+	}
+
+	if err := setAll(d, values); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceRoleUpdate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	name := d.Id()
+
+	if err := withTx(client.db, func(tx *sql.Tx) error {
+		attributeChanged := []string{
+			"password", "encrypted_password", "login", "superuser", "create_database", "create_role",
+			"inherit", "replication", "bypass_row_level_security", "connection_limit", "valid_until",
+		}
+		for _, attr := range attributeChanged {
+			if d.HasChange(attr) {
+				stmt := fmt.Sprintf("ALTER ROLE %s WITH %s", pq.QuoteIdentifier(name), roleAttributeOptions(d))
+				if _, err := tx.Exec(stmt); err != nil {
+					return fmt.Errorf("failed to update role %q: %w", name, err)
+				}
+				break
+			}
+		}
+
+		if d.HasChange("roles") {
+			old, new := d.GetChange("roles")
+			if err := applyRoleMemberships(tx, name, old.(*schema.Set).List(), new.(*schema.Set).List()); err != nil {
+				return err
+			}
+		}
+
+		if d.HasChange("search_path") {
+			if err := applyRoleSearchPath(tx, name, d.Get("search_path").([]interface{})); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceRoleRead(context.Background(), d, meta)
+}
+
+func resourceRoleDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+	name := d.Id()
+	skipReassignOwned := d.Get("skip_reassign_owned").(bool)
+	skipDropRole := d.Get("skip_drop_role").(bool)
+
+	if err := withTx(client.db, func(tx *sql.Tx) error {
+		if !skipReassignOwned {
+			// Reassign to CURRENT_USER rather than client.dataOwnerRole:
+			// csbpg_role manages arbitrary roles, not just binding users, so
+			// there is no guarantee data_owner_role exists at all.
+			if _, err := tx.Exec(fmt.Sprintf("REASSIGN OWNED BY %s TO CURRENT_USER", pq.QuoteIdentifier(name))); err != nil {
+				return fmt.Errorf("failed to reassign objects owned by role %q: %w", name, err)
+			}
+			if _, err := tx.Exec(fmt.Sprintf("DROP OWNED BY %s", pq.QuoteIdentifier(name))); err != nil {
+				return fmt.Errorf("failed to drop privileges owned by role %q: %w", name, err)
+			}
+		}
+
+		if !skipDropRole {
+			if _, err := tx.Exec(fmt.Sprintf("DROP ROLE %s", pq.QuoteIdentifier(name))); err != nil {
+				return fmt.Errorf("failed to drop role %q: %w", name, err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// roleAttributeOptions renders the CREATE ROLE / ALTER ROLE WITH clause from
+// the resource's current attribute values.
+func roleAttributeOptions(d *schema.ResourceData) string {
+	var opts []string
+
+	if d.Get("superuser").(bool) {
+		opts = append(opts, "SUPERUSER")
+	} else {
+		opts = append(opts, "NOSUPERUSER")
+	}
+	if d.Get("create_database").(bool) {
+		opts = append(opts, "CREATEDB")
+	} else {
+		opts = append(opts, "NOCREATEDB")
+	}
+	if d.Get("create_role").(bool) {
+		opts = append(opts, "CREATEROLE")
+	} else {
+		opts = append(opts, "NOCREATEROLE")
+	}
+	if d.Get("inherit").(bool) {
+		opts = append(opts, "INHERIT")
+	} else {
+		opts = append(opts, "NOINHERIT")
+	}
+	if d.Get("login").(bool) {
+		opts = append(opts, "LOGIN")
+	} else {
+		opts = append(opts, "NOLOGIN")
+	}
+	if d.Get("replication").(bool) {
+		opts = append(opts, "REPLICATION")
+	} else {
+		opts = append(opts, "NOREPLICATION")
+	}
+	if d.Get("bypass_row_level_security").(bool) {
+		opts = append(opts, "BYPASSRLS")
+	} else {
+		opts = append(opts, "NOBYPASSRLS")
+	}
+	opts = append(opts, fmt.Sprintf("CONNECTION LIMIT %d", d.Get("connection_limit").(int)))
+	if password, ok := d.GetOk("password"); ok {
+		if d.Get("encrypted_password").(bool) {
+			opts = append(opts, fmt.Sprintf("ENCRYPTED PASSWORD %s", pq.QuoteLiteral(password.(string))))
+		} else {
+			opts = append(opts, fmt.Sprintf("PASSWORD %s", pq.QuoteLiteral(password.(string))))
+		}
+	}
+	if validUntil, ok := d.GetOk("valid_until"); ok {
+		opts = append(opts, fmt.Sprintf("VALID UNTIL %s", pq.QuoteLiteral(validUntil.(string))))
+	}
+
+	return joinWithSpace(opts)
+}
+
+func applyRoleMemberships(tx *sql.Tx, role string, old, new []interface{}) error {
+	desired := map[string]bool{}
+	for _, r := range new {
+		desired[r.(string)] = true
+	}
+
+	for _, r := range old {
+		if !desired[r.(string)] {
+			if _, err := tx.Exec(fmt.Sprintf("REVOKE %s FROM %s", pq.QuoteIdentifier(r.(string)), pq.QuoteIdentifier(role))); err != nil {
+				return fmt.Errorf("failed to revoke membership of %q in %q: %w", role, r.(string), err)
+			}
+		}
+	}
+
+	existing := map[string]bool{}
+	for _, r := range old {
+		existing[r.(string)] = true
+	}
+
+	for r := range desired {
+		if !existing[r] {
+			if _, err := tx.Exec(fmt.Sprintf("GRANT %s TO %s", pq.QuoteIdentifier(r), pq.QuoteIdentifier(role))); err != nil {
+				return fmt.Errorf("failed to grant membership of %q in %q: %w", role, r, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func applyRoleSearchPath(tx *sql.Tx, role string, searchPath []interface{}) error {
+	if len(searchPath) == 0 {
+		// Clear any previously set search_path rather than leaving it in
+		// place, so that removing the attribute actually resets it.
+		if _, err := tx.Exec(fmt.Sprintf("ALTER ROLE %s RESET search_path", pq.QuoteIdentifier(role))); err != nil {
+			return fmt.Errorf("failed to reset search_path for role %q: %w", role, err)
+		}
+		return nil
+	}
+
+	schemas := make([]string, len(searchPath))
+	for i, s := range searchPath {
+		schemas[i] = pq.QuoteIdentifier(s.(string))
+	}
+
+	stmt := fmt.Sprintf("ALTER ROLE %s SET search_path TO %s", pq.QuoteIdentifier(role), joinWithComma(schemas))
+	if _, err := tx.Exec(stmt); err != nil {
+		return fmt.Errorf("failed to set search_path for role %q: %w", role, err)
+	}
+
+	return nil
+}
+
+func roleMemberships(db *sql.DB, role string) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT pg_catalog.pg_get_userbyid(roleid)
+		FROM pg_catalog.pg_auth_members
+		JOIN pg_catalog.pg_roles member ON member.oid = pg_auth_members.member
+		WHERE member.rolname = $1`, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memberships of role %q: %w", role, err)
+	}
+	defer rows.Close()
+
+	var memberships []string
+	for rows.Next() {
+		var membership string
+		if err := rows.Scan(&membership); err != nil {
+			return nil, fmt.Errorf("failed to read memberships of role %q: %w", role, err)
+		}
+		memberships = append(memberships, membership)
+	}
+
+	return memberships, nil
+}
+
+func joinWithComma(parts []string) string {
+	result := ""
+	for i, part := range parts {
+		if i > 0 {
+			result += ", "
+		}
+		result += part
+	}
+	return result
+}
@@ -365,6 +365,67 @@ EOF
 			return nil
 		})
 	})
+
+	It("migrates a legacy user off binding_group when legacy_binding_group is set", func() {
+		dataOwnerRole := "dataOwnerRole_" + uuid.New().String()
+		bindingUsername := "bindingUsername_" + uuid.New().String()
+		bindingPassword := uuid.New().String()
+
+		By("CREATING PRE-EXISTING USER AS PER THE LEGACY BROKER")
+		db, err := sql.Open("postgres", adminUserURI)
+		defer func(db *sql.DB) {
+			_ = db.Close()
+		}(db)
+		Expect(err).NotTo(HaveOccurred())
+
+		adminStatements := []string{
+			fmt.Sprintf("CREATE ROLE binding_group with role %s", pq.QuoteIdentifier(cloudsqlsuperuser)),
+			fmt.Sprintf("CREATE USER %s WITH PASSWORD %s IN ROLE binding_group", pq.QuoteIdentifier(bindingUsername), pq.QuoteLiteral(bindingPassword)),
+			fmt.Sprintf("GRANT %s TO %s", pq.QuoteIdentifier(bindingUsername), pq.QuoteIdentifier(cloudsqlsuperuser)),
+		}
+		for _, adminStatement := range adminStatements {
+			_, err = db.Exec(adminStatement)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		applyHCL(fmt.Sprintf(`
+		provider "csbpg" {
+		  host            = "%s"
+		  port            = %d
+		  username        = "%s"
+		  password        = "%s"
+		  database        = "%s"
+		  data_owner_role = "%s"
+
+		  sslrootcert = <<EOF
+%s
+EOF
+		  clientcert {
+    		cert = <<EOF
+%s
+EOF
+    		key  = <<EOF
+%s
+EOF
+  	      }
+		}
+
+		resource "csbpg_binding_user" "binding_user" {
+		  username             = "%s"
+		  password             = "%s"
+		  legacy_binding_group = "binding_group"
+		}
+		`, hostname, port, cloudsqlsuperuser, cloudsqlsuperpassword, database, dataOwnerRole,
+			postgresSSLCACert, postgresSSLClientCert, postgresSSLClientKey,
+			bindingUsername, bindingPassword),
+			func(state *terraform.State) error {
+				By("checking that the user was migrated off binding_group and onto data_owner_role")
+				Expect(query(db, fmt.Sprintf("SELECT pg_has_role('%s', 'binding_group', 'member')", bindingUsername))).To(ConsistOf(false))
+				Expect(query(db, fmt.Sprintf("SELECT pg_has_role('%s', '%s', 'member')", bindingUsername, dataOwnerRole))).To(ConsistOf(true))
+				return nil
+			},
+			func(state *terraform.State) error { return nil })
+	})
 })
 
 func replicateGCPPostgresEnv(port int, database, adminPassword string) {
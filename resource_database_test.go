@@ -0,0 +1,182 @@
+package main_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/cloudfoundry/terraform-provider-csbpg/csbpg"
+)
+
+var _ = Describe("SSL Postgres Database", func() {
+	var session *gexec.Session
+	var adminUserURI, adminPassword string
+	var port int
+
+	BeforeEach(func() {
+		var err error
+		adminPassword = uuid.New().String()
+		port = freePort()
+
+		cmd := exec.Command(
+			"docker", "run",
+			"-e", fmt.Sprintf("POSTGRES_PASSWORD=%s", adminPassword),
+			"-e", fmt.Sprintf("POSTGRES_DB=%s", defaultDatabase),
+			"-p", fmt.Sprintf("%d:5432", port),
+			"--mount", "source=ssl_postgres,destination=/mnt",
+			"-t", "postgres",
+			"-c", "config_file=/mnt/pgconf/postgresql.conf",
+			"-c", "hba_file=/mnt/pgconf/pg_hba.conf",
+		)
+		session, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() error {
+			db, err := sql.Open("postgres", buildConnectionString(adminUsername, adminPassword, port, defaultDatabase))
+			if err != nil {
+				return err
+			}
+			defer func(db *sql.DB) {
+				_ = db.Close()
+			}(db)
+			return db.Ping()
+		}).WithTimeout(10 * time.Second).WithPolling(time.Second).Should(Succeed())
+
+		adminUserURI = buildConnectionString(adminUsername, adminPassword, port, defaultDatabase)
+	})
+
+	AfterEach(func() {
+		session.Terminate()
+	})
+
+	It("creates and destroys a database", func() {
+		databaseName := "database_" + uuid.New().String()
+		applyDatabaseHCL(fmt.Sprintf(`
+		provider "csbpg" {
+		  host            = "%s"
+		  port            = %d
+		  username        = "%s"
+		  password        = "%s"
+		  database        = "%s"
+		  data_owner_role = "irrelevant_owner_role"
+
+		  sslrootcert = <<EOF
+%s
+EOF
+		  clientcert {
+    		cert = <<EOF
+%s
+EOF
+    		key  = <<EOF
+%s
+EOF
+  	      }
+		}
+
+		resource "csbpg_database" "db" {
+		  name              = "%s"
+		  connection_limit  = 5
+		}
+		`, hostname, port, adminUsername, adminPassword, defaultDatabase,
+			postgresSSLCACert, postgresSSLClientCert, postgresSSLClientKey,
+			databaseName),
+			func(state *terraform.State) error {
+				By("checking that the database exists")
+				db, err := sql.Open("postgres", adminUserURI)
+				Expect(err).NotTo(HaveOccurred())
+
+				rows, err := db.Query("SELECT FROM pg_catalog.pg_database WHERE datname = $1", databaseName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rows.Next()).To(BeTrue(), fmt.Sprintf("database %q has not been created", databaseName))
+				return nil
+			},
+			func(state *terraform.State) error {
+				By("checking that the database has been destroyed")
+				db, err := sql.Open("postgres", adminUserURI)
+				Expect(err).NotTo(HaveOccurred())
+
+				rows, err := db.Query("SELECT FROM pg_catalog.pg_database WHERE datname = $1", databaseName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rows.Next()).To(BeFalse(), fmt.Sprintf("database %q still exists", databaseName))
+				return nil
+			})
+	})
+
+	It("creates a database owned by the configured role", func() {
+		databaseName := "database_" + uuid.New().String()
+		ownerRole := "owner_" + uuid.New().String()
+
+		db, err := sql.Open("postgres", adminUserURI)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(fmt.Sprintf("CREATE ROLE %q", ownerRole))
+		Expect(err).NotTo(HaveOccurred())
+
+		applyDatabaseHCL(fmt.Sprintf(`
+		provider "csbpg" {
+		  host            = "%s"
+		  port            = %d
+		  username        = "%s"
+		  password        = "%s"
+		  database        = "%s"
+		  data_owner_role = "irrelevant_owner_role"
+
+		  sslrootcert = <<EOF
+%s
+EOF
+		  clientcert {
+    		cert = <<EOF
+%s
+EOF
+    		key  = <<EOF
+%s
+EOF
+  	      }
+		}
+
+		resource "csbpg_database" "db" {
+		  name             = "%s"
+		  owner            = "%s"
+		  connection_limit = 10
+		}
+		`, hostname, port, adminUsername, adminPassword, defaultDatabase,
+			postgresSSLCACert, postgresSSLClientCert, postgresSSLClientKey,
+			databaseName, ownerRole),
+			func(state *terraform.State) error {
+				By("checking that the database is owned by the new role")
+				rows, err := db.Query(
+					"SELECT FROM pg_catalog.pg_database WHERE datname = $1 AND pg_catalog.pg_get_userbyid(datdba) = $2",
+					databaseName, ownerRole,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rows.Next()).To(BeTrue(), "database owner was not updated")
+				return nil
+			},
+			func(state *terraform.State) error {
+				return nil
+			})
+	})
+})
+
+func applyDatabaseHCL(hcl string, checkOnCreate, checkOnDestroy resource.TestCheckFunc) {
+	resource.Test(GinkgoT(), resource.TestCase{
+		IsUnitTest: true,
+		ProviderFactories: map[string]func() (*schema.Provider, error){
+			"csbpg": func() (*schema.Provider, error) { return csbpg.Provider(), nil },
+		},
+		CheckDestroy: checkOnDestroy,
+		Steps: []resource.TestStep{{
+			ResourceName: "csbpg_database.db",
+			Config:       hcl,
+			Check:        checkOnCreate,
+		}},
+	})
+}
@@ -0,0 +1,199 @@
+package main_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/cloudfoundry/terraform-provider-csbpg/csbpg"
+)
+
+var _ = Describe("SSL Postgres Grant", func() {
+	var session *gexec.Session
+	var adminUserURI, adminPassword, database string
+	var port int
+	var grantedRole string
+
+	BeforeEach(func() {
+		var err error
+		adminPassword = uuid.New().String()
+		database = uuid.New().String()
+		grantedRole = "role_" + uuid.New().String()
+		port = freePort()
+
+		cmd := exec.Command(
+			"docker", "run",
+			"-e", fmt.Sprintf("POSTGRES_PASSWORD=%s", adminPassword),
+			"-e", fmt.Sprintf("POSTGRES_DB=%s", defaultDatabase),
+			"-p", fmt.Sprintf("%d:5432", port),
+			"--mount", "source=ssl_postgres,destination=/mnt",
+			"-t", "postgres",
+			"-c", "config_file=/mnt/pgconf/postgresql.conf",
+			"-c", "hba_file=/mnt/pgconf/pg_hba.conf",
+		)
+		session, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() error {
+			db, err := sql.Open("postgres", buildConnectionString(adminUsername, adminPassword, port, defaultDatabase))
+			if err != nil {
+				return err
+			}
+			defer func(db *sql.DB) {
+				_ = db.Close()
+			}(db)
+			return db.Ping()
+		}).WithTimeout(10 * time.Second).WithPolling(time.Second).Should(Succeed())
+
+		replicateGCPPostgresEnv(port, database, adminPassword)
+		adminUserURI = buildConnectionString(adminUsername, adminPassword, port, database)
+
+		db, err := sql.Open("postgres", adminUserURI)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(fmt.Sprintf("CREATE ROLE %q", grantedRole))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec("CREATE TABLE widgets (id INT PRIMARY KEY)")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		session.Terminate()
+	})
+
+	It("grants and revokes table privileges", func() {
+		applyGrantHCL(fmt.Sprintf(`
+		provider "csbpg" {
+		  host            = "%s"
+		  port            = %d
+		  username        = "%s"
+		  password        = "%s"
+		  database        = "%s"
+		  data_owner_role = "irrelevant_owner_role"
+
+		  sslrootcert = <<EOF
+%s
+EOF
+		  clientcert {
+    		cert = <<EOF
+%s
+EOF
+    		key  = <<EOF
+%s
+EOF
+  	      }
+		}
+
+		resource "csbpg_grant" "widgets_select" {
+		  role        = "%s"
+		  database    = "%s"
+		  schema      = "public"
+		  object_type = "table"
+		  objects     = ["widgets"]
+		  privileges  = ["SELECT"]
+		}
+		`, hostname, port, adminUsername, adminPassword, database,
+			postgresSSLCACert, postgresSSLClientCert, postgresSSLClientKey,
+			grantedRole, database),
+			func(state *terraform.State) error {
+				By("checking that the privilege was granted")
+				db, err := sql.Open("postgres", adminUserURI)
+				Expect(err).NotTo(HaveOccurred())
+
+				rows, err := db.Query(
+					"SELECT FROM information_schema.role_table_grants WHERE grantee = $1 AND table_name = 'widgets' AND privilege_type = 'SELECT'",
+					grantedRole,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rows.Next()).To(BeTrue(), "SELECT was not granted")
+				return nil
+			},
+			func(state *terraform.State) error {
+				By("checking that the privilege was revoked")
+				db, err := sql.Open("postgres", adminUserURI)
+				Expect(err).NotTo(HaveOccurred())
+
+				rows, err := db.Query(
+					"SELECT FROM information_schema.role_table_grants WHERE grantee = $1 AND table_name = 'widgets' AND privilege_type = 'SELECT'",
+					grantedRole,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rows.Next()).To(BeFalse(), "SELECT was not revoked")
+				return nil
+			})
+	})
+
+	It("sets default privileges for future tables", func() {
+		applyGrantHCL(fmt.Sprintf(`
+		provider "csbpg" {
+		  host            = "%s"
+		  port            = %d
+		  username        = "%s"
+		  password        = "%s"
+		  database        = "%s"
+		  data_owner_role = "irrelevant_owner_role"
+
+		  sslrootcert = <<EOF
+%s
+EOF
+		  clientcert {
+    		cert = <<EOF
+%s
+EOF
+    		key  = <<EOF
+%s
+EOF
+  	      }
+		}
+
+		resource "csbpg_default_privileges" "widgets_default" {
+		  owner       = "%s"
+		  role        = "%s"
+		  schema      = "public"
+		  object_type = "table"
+		  privileges  = ["SELECT"]
+		}
+		`, hostname, port, adminUsername, adminPassword, database,
+			postgresSSLCACert, postgresSSLClientCert, postgresSSLClientKey,
+			adminUsername, grantedRole),
+			func(state *terraform.State) error {
+				By("checking that the default privilege is in place")
+				db, err := sql.Open("postgres", adminUserURI)
+				Expect(err).NotTo(HaveOccurred())
+
+				rows, err := db.Query(`
+					SELECT FROM pg_catalog.pg_default_acl
+					JOIN pg_catalog.pg_roles ON pg_roles.oid = pg_default_acl.defaclrole
+					JOIN pg_catalog.pg_namespace ON pg_namespace.oid = pg_default_acl.defaclnamespace
+					WHERE pg_roles.rolname = $1 AND pg_namespace.nspname = 'public'`,
+					adminUsername,
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rows.Next()).To(BeTrue(), "default privilege was not set")
+				return nil
+			},
+			func(state *terraform.State) error { return nil })
+	})
+})
+
+func applyGrantHCL(hcl string, checkOnCreate, checkOnDestroy resource.TestCheckFunc) {
+	resource.Test(GinkgoT(), resource.TestCase{
+		IsUnitTest: true,
+		ProviderFactories: map[string]func() (*schema.Provider, error){
+			"csbpg": func() (*schema.Provider, error) { return csbpg.Provider(), nil },
+		},
+		CheckDestroy: checkOnDestroy,
+		Steps: []resource.TestStep{{
+			Config: hcl,
+			Check:  checkOnCreate,
+		}},
+	})
+}
@@ -0,0 +1,174 @@
+package main_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/cloudfoundry/terraform-provider-csbpg/csbpg"
+)
+
+var _ = Describe("SSL Postgres Role", func() {
+	var session *gexec.Session
+	var adminUserURI, adminPassword, database string
+	var port int
+
+	BeforeEach(func() {
+		var err error
+		adminPassword = uuid.New().String()
+		database = uuid.New().String()
+		port = freePort()
+
+		cmd := exec.Command(
+			"docker", "run",
+			"-e", fmt.Sprintf("POSTGRES_PASSWORD=%s", adminPassword),
+			"-e", fmt.Sprintf("POSTGRES_DB=%s", defaultDatabase),
+			"-p", fmt.Sprintf("%d:5432", port),
+			"--mount", "source=ssl_postgres,destination=/mnt",
+			"-t", "postgres",
+			"-c", "config_file=/mnt/pgconf/postgresql.conf",
+			"-c", "hba_file=/mnt/pgconf/pg_hba.conf",
+		)
+		session, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() error {
+			db, err := sql.Open("postgres", buildConnectionString(adminUsername, adminPassword, port, defaultDatabase))
+			if err != nil {
+				return err
+			}
+			defer func(db *sql.DB) {
+				_ = db.Close()
+			}(db)
+			return db.Ping()
+		}).WithTimeout(10 * time.Second).WithPolling(time.Second).Should(Succeed())
+
+		replicateGCPPostgresEnv(port, database, adminPassword)
+		adminUserURI = buildConnectionString(adminUsername, adminPassword, port, database)
+	})
+
+	AfterEach(func() {
+		session.Terminate()
+	})
+
+	It("creates a role under the cloudsqlsuperuser path", func() {
+		roleName := "role_" + uuid.New().String()
+		applyRoleHCL(fmt.Sprintf(`
+		provider "csbpg" {
+		  host            = "%s"
+		  port            = %d
+		  username        = "%s"
+		  password        = "%s"
+		  database        = "%s"
+		  data_owner_role = "irrelevant_owner_role"
+
+		  sslrootcert = <<EOF
+%s
+EOF
+		  clientcert {
+    		cert = <<EOF
+%s
+EOF
+    		key  = <<EOF
+%s
+EOF
+  	      }
+		}
+
+		resource "csbpg_role" "role" {
+		  name  = "%s"
+		  login = true
+		}
+		`, hostname, port, cloudsqlsuperuser, cloudsqlsuperpassword, database,
+			postgresSSLCACert, postgresSSLClientCert, postgresSSLClientKey,
+			roleName),
+			func(state *terraform.State) error {
+				By("checking that the role is created")
+				db, err := sql.Open("postgres", adminUserURI)
+				Expect(err).NotTo(HaveOccurred())
+
+				rows, err := db.Query("SELECT FROM pg_catalog.pg_roles WHERE rolname = $1 AND rolcanlogin", roleName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rows.Next()).To(BeTrue(), fmt.Sprintf("role %q has not been created", roleName))
+				return nil
+			},
+			func(state *terraform.State) error {
+				By("checking that the role is destroyed")
+				db, err := sql.Open("postgres", adminUserURI)
+				Expect(err).NotTo(HaveOccurred())
+
+				rows, err := db.Query("SELECT FROM pg_catalog.pg_roles WHERE rolname = $1", roleName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rows.Next()).To(BeFalse(), fmt.Sprintf("role %q still exists", roleName))
+				return nil
+			})
+	})
+
+	It("leaves the role in place when skip_drop_role is set", func() {
+		roleName := "role_" + uuid.New().String()
+		applyRoleHCL(fmt.Sprintf(`
+		provider "csbpg" {
+		  host            = "%s"
+		  port            = %d
+		  username        = "%s"
+		  password        = "%s"
+		  database        = "%s"
+		  data_owner_role = "irrelevant_owner_role"
+
+		  sslrootcert = <<EOF
+%s
+EOF
+		  clientcert {
+    		cert = <<EOF
+%s
+EOF
+    		key  = <<EOF
+%s
+EOF
+  	      }
+		}
+
+		resource "csbpg_role" "role" {
+		  name           = "%s"
+		  skip_drop_role = true
+		}
+		`, hostname, port, cloudsqlsuperuser, cloudsqlsuperpassword, database,
+			postgresSSLCACert, postgresSSLClientCert, postgresSSLClientKey,
+			roleName),
+			func(state *terraform.State) error { return nil },
+			func(state *terraform.State) error {
+				By("checking that the role still exists")
+				db, err := sql.Open("postgres", adminUserURI)
+				Expect(err).NotTo(HaveOccurred())
+
+				rows, err := db.Query("SELECT FROM pg_catalog.pg_roles WHERE rolname = $1", roleName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rows.Next()).To(BeTrue(), fmt.Sprintf("role %q was dropped despite skip_drop_role", roleName))
+				return nil
+			})
+	})
+})
+
+func applyRoleHCL(hcl string, checkOnCreate, checkOnDestroy resource.TestCheckFunc) {
+	resource.Test(GinkgoT(), resource.TestCase{
+		IsUnitTest: true,
+		ProviderFactories: map[string]func() (*schema.Provider, error){
+			"csbpg": func() (*schema.Provider, error) { return csbpg.Provider(), nil },
+		},
+		CheckDestroy: checkOnDestroy,
+		Steps: []resource.TestStep{{
+			ResourceName: "csbpg_role.role",
+			Config:       hcl,
+			Check:        checkOnCreate,
+		}},
+	})
+}